@@ -0,0 +1,75 @@
+// Command splice-play decodes a .splice file and plays it, writing the
+// rendered audio as raw little-endian float32 PCM to an output file.
+//
+// Usage:
+//
+//	splice-play -pattern drum.splice -samples ./samples -out out.raw
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+
+	drum "github.com/jppunnett/gochal1"
+	"github.com/jppunnett/gochal1/filesampler"
+)
+
+// rawPCMWriter is a drum.Output that appends each step's mixed samples, as
+// little-endian float32 PCM, to an io.Writer. A real application would
+// instead wire Write to an audio device via a package such as oto or
+// portaudio.
+type rawPCMWriter struct {
+	w io.Writer
+}
+
+func (o *rawPCMWriter) Write(samples []float32) error {
+	buf := make([]byte, 4*len(samples))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(s))
+	}
+
+	_, err := o.w.Write(buf)
+	return err
+}
+
+func main() {
+	pattern := flag.String("pattern", "", "path to a .splice file")
+	samples := flag.String("samples", "", "directory of <instrument>.wav sample files")
+	out := flag.String("out", "out.raw", "file to write the rendered little-endian float32 PCM to")
+	loops := flag.Int("loops", 1, "number of times to play the pattern")
+	flag.Parse()
+
+	if *pattern == "" || *samples == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	p, err := drum.DecodeFile(*pattern)
+	if err != nil {
+		log.Fatalf("decoding %s: %v", *pattern, err)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("creating %s: %v", *out, err)
+	}
+	defer f.Close()
+
+	opts := drum.PlayOptions{
+		Sampler: filesampler.New(*samples),
+		Output:  &rawPCMWriter{w: f},
+		Loops:   *loops,
+	}
+
+	if err := p.Play(context.Background(), opts); err != nil {
+		log.Fatalf("playing %s: %v", *pattern, err)
+	}
+
+	fmt.Printf("wrote %s\n", *out)
+}
@@ -58,6 +58,26 @@ Tempo: 240
 Tempo: 999
 (1) Kick	|x---|----|x---|----|
 (2) HiHat	|x-x-|x-x-|x-x-|x-x-|
+`,
+		},
+		// pattern_large.splice has a payload over 255 bytes, so its
+		// remaining-bytes field only decodes correctly when read as an
+		// 8-byte big-endian count rather than a single byte.
+		{"pattern_large.splice",
+			`Saved with HW Version: 0.808-alpha
+Tempo: 200
+(0) kick	|x---|x---|x---|x---|
+(1) snare	|x---|x---|x---|x---|
+(2) clap	|x---|x---|x---|x---|
+(3) hh-closed	|x---|x---|x---|x---|
+(4) hh-open	|x---|x---|x---|x---|
+(5) cowbell	|x---|x---|x---|x---|
+(6) low-tom	|x---|x---|x---|x---|
+(7) mid-tom	|x---|x---|x---|x---|
+(8) hi-tom	|x---|x---|x---|x---|
+(9) rimshot	|x---|x---|x---|x---|
+(10) shaker	|x---|x---|x---|x---|
+(11) conga	|x---|x---|x---|x---|
 `,
 		},
 	}
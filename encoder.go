@@ -0,0 +1,62 @@
+package drum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+)
+
+// ErrTrackNameTooLong indicates a Track's instrument name does not fit in
+// the single-byte nameLen field that MarshalBinary writes.
+var ErrTrackNameTooLong = errors.New("drum: track name is too long to encode")
+
+// MarshalBinary encodes p into the exact byte layout that DecodeFile
+// consumes: the six-byte "SPLICE" magic, the 8-byte big-endian
+// remaining-bytes length field, a zero-padded hardware-version field, a
+// little-endian float32 tempo, and the tracks laid out as
+// id(4)|nameLen(1)|name|steps(16).
+func (p *Pattern) MarshalBinary() ([]byte, error) {
+	var body bytes.Buffer
+
+	hwver := make([]byte, szHwVerFld)
+	copy(hwver, p.hwver)
+	body.Write(hwver)
+
+	if err := binary.Write(&body, binary.LittleEndian, p.tempo); err != nil {
+		return nil, err
+	}
+
+	for _, t := range p.tracks {
+		if len(t.name) > 0xff {
+			return nil, ErrTrackNameTooLong
+		}
+
+		body.Write([]byte{byte(t.id), 0, 0, 0})
+		body.WriteByte(byte(len(t.name)))
+		body.WriteString(t.name)
+		body.Write(t.steps[:])
+	}
+
+	out := make([]byte, 0, posStartOfData+body.Len())
+	out = append(out, []byte("SPLICE")...)
+
+	szField := make([]byte, szRemBytesFld)
+	binary.BigEndian.PutUint64(szField, uint64(body.Len()))
+	out = append(out, szField...)
+
+	out = append(out, body.Bytes()...)
+
+	return out, nil
+}
+
+// EncodeFile writes p to path in the .splice format produced by
+// MarshalBinary.
+func EncodeFile(p *Pattern, path string) error {
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,110 @@
+package drum
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestMarshalBinaryRoundTrip(t *testing.T) {
+	names := []string{
+		"pattern_1.splice",
+		"pattern_2.splice",
+		"pattern_3.splice",
+		"pattern_4.splice",
+		"pattern_5.splice",
+		"pattern_large.splice",
+	}
+
+	for _, name := range names {
+		fixture := path.Join("fixtures", name)
+
+		original, err := ioutil.ReadFile(fixture)
+		if err != nil {
+			t.Fatalf("could not read %s: %v", fixture, err)
+		}
+
+		p, err := DecodeFile(fixture)
+		if err != nil {
+			t.Fatalf("could not decode %s: %v", fixture, err)
+		}
+
+		encoded, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatalf("could not marshal %s: %v", fixture, err)
+		}
+
+		if !bytes.Equal(original, encoded) {
+			t.Errorf("%s: re-encoded bytes do not match original\noriginal: % x\nencoded:  % x", name, original, encoded)
+		}
+	}
+}
+
+func TestEncodeFile(t *testing.T) {
+	fixture := path.Join("fixtures", "pattern_1.splice")
+
+	p, err := DecodeFile(fixture)
+	if err != nil {
+		t.Fatalf("could not decode %s: %v", fixture, err)
+	}
+
+	out := path.Join("fixtures", "pattern_1_roundtrip.splice")
+	if err := EncodeFile(p, out); err != nil {
+		t.Fatalf("could not encode %s: %v", out, err)
+	}
+
+	decoded, err := DecodeFile(out)
+	if err != nil {
+		t.Fatalf("could not decode %s: %v", out, err)
+	}
+
+	if decoded.String() != p.String() {
+		t.Fatalf("round-tripped pattern does not match original\ngot:\n%s\nwant:\n%s", decoded, p)
+	}
+}
+
+func TestMarshalBinaryTrackNameTooLong(t *testing.T) {
+	p := NewPattern("0.808-alpha", 120)
+	p.AddTrack(NewTrack(0, strings.Repeat("x", 0x100)))
+
+	if _, err := p.MarshalBinary(); err != ErrTrackNameTooLong {
+		t.Fatalf("got %v, want ErrTrackNameTooLong", err)
+	}
+}
+
+func TestBuildPatternFromScratch(t *testing.T) {
+	p := NewPattern("0.808-alpha", 120)
+
+	kick := NewTrack(0, "kick")
+	kick.SetStep(0, true)
+	kick.SetStep(4, true)
+	kick.SetStep(8, true)
+	kick.SetStep(12, true)
+	p.AddTrack(kick)
+
+	want := "Saved with HW Version: 0.808-alpha\nTempo: 120\n(0) kick\t|x---|x---|x---|x---|\n"
+	if p.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", p, want)
+	}
+
+	data, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("could not marshal pattern: %v", err)
+	}
+
+	out := path.Join("fixtures", "scratch.splice")
+	if err := ioutil.WriteFile(out, data, 0644); err != nil {
+		t.Fatalf("could not write %s: %v", out, err)
+	}
+
+	decoded, err := DecodeFile(out)
+	if err != nil {
+		t.Fatalf("could not decode %s: %v", out, err)
+	}
+
+	if decoded.String() != want {
+		t.Fatalf("decoded pattern does not match\ngot:\n%s\nwant:\n%s", decoded, want)
+	}
+}
@@ -0,0 +1,169 @@
+// Package filesampler implements drum.Sampler by loading one WAV file per
+// instrument from a directory.
+package filesampler
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ErrUnsupportedFormat indicates a WAV file that isn't PCM, or isn't 8 or
+// 16-bit.
+var ErrUnsupportedFormat = errors.New("filesampler: unsupported WAV format")
+
+const (
+	pcmFormat        = 1
+	bitsPerSample8   = 8
+	bitsPerSample16  = 16
+	maxInt16         = 1 << 15
+	maxUint8Centered = 1 << 7
+)
+
+// Sampler loads instrument samples from WAV files named "<instrument>.wav"
+// in Dir.
+type Sampler struct {
+	Dir string
+}
+
+// New returns a Sampler that loads instrument samples from WAV files in dir.
+func New(dir string) *Sampler {
+	return &Sampler{Dir: dir}
+}
+
+// Sample implements drum.Sampler. It opens "<instrument>.wav" in s.Dir and
+// decodes it into mono, normalized ([-1, 1]) float32 PCM samples.
+func (s *Sampler) Sample(instrument string) ([]float32, error) {
+	f, err := os.Open(filepath.Join(s.Dir, instrument+".wav"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return decodeWAV(f)
+}
+
+// wavFmt holds the fields of a WAV "fmt " chunk that decodeWAV needs.
+type wavFmt struct {
+	audioFormat   uint16
+	numChannels   uint16
+	bitsPerSample uint16
+}
+
+// decodeWAV reads a canonical RIFF/WAVE file and returns its samples as
+// mono, normalized float32 PCM, downmixing multi-channel audio by averaging
+// channels.
+func decodeWAV(r io.Reader) ([]float32, error) {
+	var riffHdr [12]byte
+	if _, err := io.ReadFull(r, riffHdr[:]); err != nil {
+		return nil, err
+	}
+	if string(riffHdr[0:4]) != "RIFF" || string(riffHdr[8:12]) != "WAVE" {
+		return nil, ErrUnsupportedFormat
+	}
+
+	var format wavFmt
+	var haveFormat bool
+
+	for {
+		var chunkHdr [8]byte
+		if _, err := io.ReadFull(r, chunkHdr[:]); err != nil {
+			if err == io.ErrUnexpectedEOF || err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+
+		id := string(chunkHdr[0:4])
+		size := binary.LittleEndian.Uint32(chunkHdr[4:8])
+
+		switch id {
+		case "fmt ":
+			body := make([]byte, size)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, err
+			}
+			format = wavFmt{
+				audioFormat:   binary.LittleEndian.Uint16(body[0:2]),
+				numChannels:   binary.LittleEndian.Uint16(body[2:4]),
+				bitsPerSample: binary.LittleEndian.Uint16(body[14:16]),
+			}
+			haveFormat = true
+
+		case "data":
+			if !haveFormat {
+				return nil, ErrUnsupportedFormat
+			}
+			body := make([]byte, size)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, err
+			}
+			return pcmToFloat32(body, format)
+
+		default:
+			if _, err := io.CopyN(ioutil.Discard, r, int64(size)); err != nil {
+				return nil, err
+			}
+		}
+
+		// Chunks are padded to an even number of bytes.
+		if size%2 == 1 {
+			if _, err := io.CopyN(ioutil.Discard, r, 1); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("filesampler: no data chunk found")
+}
+
+// pcmToFloat32 converts raw PCM bytes to normalized, downmixed-to-mono
+// float32 samples.
+func pcmToFloat32(data []byte, format wavFmt) ([]float32, error) {
+	if format.audioFormat != pcmFormat {
+		return nil, ErrUnsupportedFormat
+	}
+
+	channels := int(format.numChannels)
+	if channels < 1 {
+		return nil, ErrUnsupportedFormat
+	}
+
+	var frameSamples func(frame []byte) float32
+	var bytesPerSample int
+
+	switch format.bitsPerSample {
+	case bitsPerSample8:
+		bytesPerSample = 1
+		frameSamples = func(b []byte) float32 {
+			return (float32(b[0]) - maxUint8Centered) / maxUint8Centered
+		}
+	case bitsPerSample16:
+		bytesPerSample = 2
+		frameSamples = func(b []byte) float32 {
+			return float32(int16(binary.LittleEndian.Uint16(b))) / maxInt16
+		}
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	frameSize := bytesPerSample * channels
+	numFrames := len(data) / frameSize
+
+	samples := make([]float32, numFrames)
+	for i := 0; i < numFrames; i++ {
+		frame := data[i*frameSize : (i+1)*frameSize]
+
+		var sum float32
+		for c := 0; c < channels; c++ {
+			sum += frameSamples(frame[c*bytesPerSample : (c+1)*bytesPerSample])
+		}
+		samples[i] = sum / float32(channels)
+	}
+
+	return samples, nil
+}
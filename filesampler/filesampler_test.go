@@ -0,0 +1,34 @@
+package filesampler
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSample(t *testing.T) {
+	s := New("testdata")
+
+	samples, err := s.Sample("kick")
+	if err != nil {
+		t.Fatalf("Sample returned an error: %v", err)
+	}
+
+	want := []float32{3000.0 / maxInt16, -3000.0 / maxInt16, 1000.0 / maxInt16, -1000.0 / maxInt16}
+	if len(samples) != len(want) {
+		t.Fatalf("got %d samples, want %d", len(samples), len(want))
+	}
+
+	for i := range want {
+		if math.Abs(float64(samples[i]-want[i])) > 1e-6 {
+			t.Errorf("sample %d = %v, want %v", i, samples[i], want[i])
+		}
+	}
+}
+
+func TestSampleMissingFile(t *testing.T) {
+	s := New("testdata")
+
+	if _, err := s.Sample("no-such-instrument"); err == nil {
+		t.Fatal("expected an error for a missing instrument, got nil")
+	}
+}
@@ -0,0 +1,176 @@
+// Package midi converts a drum.Pattern into a Standard MIDI File (SMF).
+package midi
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	drum "github.com/jppunnett/gochal1"
+)
+
+// ErrUnknownInstrument indicates a Pattern track's instrument name has no
+// entry in the InstrumentMap passed to WriteSMF.
+var ErrUnknownInstrument = errors.New("midi: pattern track has no entry in InstrumentMap")
+
+// InstrumentMap maps instrument names, as used by drum.Track.Name, to
+// General MIDI percussion note numbers.
+type InstrumentMap map[string]uint8
+
+// DefaultInstrumentMap maps the instrument names found in the package's
+// .splice fixtures to General MIDI percussion notes.
+var DefaultInstrumentMap = InstrumentMap{
+	"kick":     36,
+	"snare":    38,
+	"clap":     39,
+	"hh-close": 42,
+	"hh-open":  46,
+	"cowbell":  56,
+	"low-tom":  45,
+	"mid-tom":  47,
+	"hi-tom":   50,
+}
+
+const (
+	division     = 480 // ticks per quarter note
+	ticksPerStep = division / 4
+
+	noteOnVelocity  = 100
+	noteOffVelocity = 0
+	noteDuration    = ticksPerStep / 2
+
+	percussionChannel = 9 // MIDI channel 10, zero-indexed
+	statusNoteOn      = 0x90
+	statusNoteOff     = 0x80
+
+	metaEvent    = 0xff
+	metaSetTempo = 0x51
+	metaEndOfTrk = 0x2f
+)
+
+// WriteSMF writes p to w as a Type-1 Standard MIDI File: one track per drum
+// voice on MIDI channel 10, with each active step emitting a NoteOn/NoteOff
+// pair, preceded by a conductor track carrying p's tempo.
+func WriteSMF(w io.Writer, p *drum.Pattern, mapping InstrumentMap) error {
+	tracks := p.Tracks()
+
+	voiceChunks := make([][]byte, 0, len(tracks))
+	for _, t := range tracks {
+		note, ok := mapping[t.Name()]
+		if !ok {
+			return ErrUnknownInstrument
+		}
+		voiceChunks = append(voiceChunks, voiceTrackChunk(t, note))
+	}
+
+	if _, err := w.Write(headerChunk(len(voiceChunks) + 1)); err != nil {
+		return err
+	}
+	if _, err := w.Write(conductorTrackChunk(p.Tempo())); err != nil {
+		return err
+	}
+	for _, chunk := range voiceChunks {
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// headerChunk builds the 14-byte MThd header for a format-1 file with
+// ntrks tracks.
+func headerChunk(ntrks int) []byte {
+	hdr := make([]byte, 0, 14)
+	hdr = append(hdr, []byte("MThd")...)
+	hdr = append(hdr, 0, 0, 0, 6) // chunk length
+	hdr = append(hdr, 0, 1)       // format 1
+	hdr = append(hdr, uint8(ntrks>>8), uint8(ntrks&0xff))
+	hdr = append(hdr, uint8(division>>8), uint8(division&0xff))
+
+	return hdr
+}
+
+// conductorTrackChunk builds the MTrk chunk containing only a Set Tempo
+// meta event derived from tempo, followed by End-of-Track.
+func conductorTrackChunk(tempo float32) []byte {
+	usPerQuarter := uint32(60000000 / tempo)
+
+	var body []byte
+	body = appendVarLen(body, 0)
+	body = append(body, metaEvent, metaSetTempo, 3,
+		uint8(usPerQuarter>>16), uint8(usPerQuarter>>8), uint8(usPerQuarter))
+	body = appendEndOfTrack(body)
+
+	return mtrkChunk(body)
+}
+
+// voiceTrackChunk builds the MTrk chunk for a single drum voice: a
+// NoteOn/NoteOff pair for each step of t that is on, followed by
+// End-of-Track.
+func voiceTrackChunk(t *drum.Track, note uint8) []byte {
+	var body []byte
+	lastTick := uint32(0)
+
+	for step := 0; step < 16; step++ {
+		if !t.StepOn(step) {
+			continue
+		}
+
+		onTick := uint32(step) * ticksPerStep
+		offTick := onTick + noteDuration
+
+		body = appendVarLen(body, onTick-lastTick)
+		body = append(body, statusNoteOn|percussionChannel, note, noteOnVelocity)
+
+		body = appendVarLen(body, offTick-onTick)
+		body = append(body, statusNoteOff|percussionChannel, note, noteOffVelocity)
+
+		lastTick = offTick
+	}
+
+	body = appendEndOfTrack(body)
+
+	return mtrkChunk(body)
+}
+
+// appendEndOfTrack appends the standard End-of-Track meta event
+// (FF 2F 00), with a zero delta time.
+func appendEndOfTrack(body []byte) []byte {
+	body = appendVarLen(body, 0)
+	return append(body, metaEvent, metaEndOfTrk, 0)
+}
+
+// mtrkChunk wraps body in an "MTrk" chunk header carrying its length.
+func mtrkChunk(body []byte) []byte {
+	chunk := make([]byte, 0, 8+len(body))
+	chunk = append(chunk, []byte("MTrk")...)
+
+	var lenBuf [4]byte
+	binary.BigEndian.PutUint32(lenBuf[:], uint32(len(body)))
+	chunk = append(chunk, lenBuf[:]...)
+
+	return append(chunk, body...)
+}
+
+// appendVarLen appends value to buf encoded as a MIDI variable-length
+// quantity.
+func appendVarLen(buf []byte, value uint32) []byte {
+	var stack [5]byte
+	n := 0
+
+	stack[n] = byte(value & 0x7f)
+	n++
+	value >>= 7
+	for value > 0 {
+		stack[n] = byte(value&0x7f) | 0x80
+		n++
+		value >>= 7
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		buf = append(buf, stack[i])
+	}
+
+	return buf
+}
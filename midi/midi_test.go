@@ -0,0 +1,142 @@
+package midi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path"
+	"strings"
+	"testing"
+
+	drum "github.com/jppunnett/gochal1"
+)
+
+func countActiveSteps(p *drum.Pattern) int {
+	n := 0
+	for _, t := range p.Tracks() {
+		for step := 0; step < 16; step++ {
+			if t.StepOn(step) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// readVarLen reads a MIDI variable-length quantity starting at data[i] and
+// returns its value and the index just past it.
+func readVarLen(data []byte, i int) (uint32, int) {
+	var value uint32
+	for {
+		b := data[i]
+		i++
+		value = value<<7 | uint32(b&0x7f)
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	return value, i
+}
+
+// countNoteOns walks every MTrk chunk in an SMF produced by WriteSMF and
+// counts NoteOn events on the percussion channel.
+func countNoteOns(data []byte) int {
+	n := 0
+	i := 14 // past the MThd chunk
+
+	for i < len(data) {
+		if string(data[i:i+4]) != "MTrk" {
+			panic("countNoteOns: expected MTrk chunk")
+		}
+		chunkLen := int(binary.BigEndian.Uint32(data[i+4 : i+8]))
+		body := data[i+8 : i+8+chunkLen]
+		i += 8 + chunkLen
+
+		for j := 0; j < len(body); {
+			_, next := readVarLen(body, j) // delta time
+			j = next
+
+			status := body[j]
+			j++
+
+			switch {
+			case status == metaEvent:
+				j++ // meta type
+				length := int(body[j])
+				j += 1 + length
+			case status == statusNoteOn|percussionChannel:
+				n++
+				j += 2
+			case status == statusNoteOff|percussionChannel:
+				j += 2
+			default:
+				panic("countNoteOns: unexpected status byte")
+			}
+		}
+	}
+
+	return n
+}
+
+func TestWriteSMF(t *testing.T) {
+	names := []string{
+		"pattern_1.splice",
+		"pattern_2.splice",
+		"pattern_3.splice",
+	}
+
+	for _, name := range names {
+		fixture := path.Join("..", "fixtures", name)
+
+		p, err := drum.DecodeFile(fixture)
+		if err != nil {
+			t.Fatalf("could not decode %s: %v", fixture, err)
+		}
+
+		var buf bytes.Buffer
+		if err := WriteSMF(&buf, p, DefaultInstrumentMap); err != nil {
+			t.Fatalf("WriteSMF(%s) returned an error: %v", name, err)
+		}
+
+		data := buf.Bytes()
+
+		if !strings.HasPrefix(string(data[:4]), "MThd") {
+			t.Fatalf("%s: missing MThd header", name)
+		}
+
+		chunkLen := binary.BigEndian.Uint32(data[4:8])
+		if chunkLen != 6 {
+			t.Errorf("%s: MThd length = %d, want 6", name, chunkLen)
+		}
+
+		format := binary.BigEndian.Uint16(data[8:10])
+		if format != 1 {
+			t.Errorf("%s: format = %d, want 1", name, format)
+		}
+
+		ntrks := binary.BigEndian.Uint16(data[10:12])
+		if int(ntrks) != len(p.Tracks())+1 {
+			t.Errorf("%s: ntrks = %d, want %d", name, ntrks, len(p.Tracks())+1)
+		}
+
+		div := binary.BigEndian.Uint16(data[12:14])
+		if div != division {
+			t.Errorf("%s: division = %d, want %d", name, div, division)
+		}
+
+		if want, got := countActiveSteps(p), countNoteOns(data); got != want {
+			t.Errorf("%s: got %d NoteOn events, want %d", name, got, want)
+		}
+	}
+}
+
+func TestWriteSMFUnknownInstrument(t *testing.T) {
+	p := drum.NewPattern("0.808-alpha", 120)
+	t1 := drum.NewTrack(0, "not-a-gm-instrument")
+	t1.SetStep(0, true)
+	p.AddTrack(t1)
+
+	var buf bytes.Buffer
+	if err := WriteSMF(&buf, p, DefaultInstrumentMap); err != ErrUnknownInstrument {
+		t.Fatalf("got %v, want ErrUnknownInstrument", err)
+	}
+}
@@ -0,0 +1,120 @@
+package drum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNoSampler indicates that PlayOptions was given without a Sampler.
+	ErrNoSampler = errors.New("drum: PlayOptions.Sampler is required")
+
+	// ErrNoOutput indicates that PlayOptions was given without an Output.
+	ErrNoOutput = errors.New("drum: PlayOptions.Output is required")
+)
+
+// Sampler supplies the PCM samples for a named instrument, e.g. the
+// instrument name of a Track.
+type Sampler interface {
+	Sample(instrument string) ([]float32, error)
+}
+
+// Output is the sink that rendered audio is written to, one step's worth of
+// samples at a time.
+type Output interface {
+	Write(samples []float32) error
+}
+
+// PlayOptions configures Pattern.Play.
+type PlayOptions struct {
+	// Sampler supplies the PCM samples played for each track's instrument.
+	Sampler Sampler
+
+	// Output receives the mixed samples for each step, in order.
+	Output Output
+
+	// Loops is the number of times to play the pattern through. Zero or
+	// negative means play it once.
+	Loops int
+}
+
+// Play renders p through opts.Output, one 16th-note step at a time, using
+// samples from opts.Sampler. Each step's duration is derived from p's tempo
+// (60/tempo/4 seconds) and the steps of all tracks active on it are mixed
+// together before being written. Play honors ctx cancellation, stopping
+// before the next step is scheduled.
+func (p *Pattern) Play(ctx context.Context, opts PlayOptions) error {
+	if opts.Sampler == nil {
+		return ErrNoSampler
+	}
+	if opts.Output == nil {
+		return ErrNoOutput
+	}
+
+	loops := opts.Loops
+	if loops <= 0 {
+		loops = 1
+	}
+
+	samples := make(map[string][]float32, len(p.tracks))
+	for _, t := range p.tracks {
+		s, err := opts.Sampler.Sample(t.name)
+		if err != nil {
+			return fmt.Errorf("drum: loading sample for %q: %v", t.name, err)
+		}
+		samples[t.name] = s
+	}
+
+	stepDur := stepDuration(p.tempo)
+
+	for loop := 0; loop < loops; loop++ {
+		for step := 0; step < numSteps; step++ {
+			if err := opts.Output.Write(mixStep(p.tracks, samples, step)); err != nil {
+				return err
+			}
+
+			timer := time.NewTimer(stepDur)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+
+	return nil
+}
+
+// stepDuration is the real-time duration of a single 16th-note step at the
+// given BPM.
+func stepDuration(tempo float32) time.Duration {
+	seconds := 60 / float64(tempo) / 4
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// mixStep sums the samples of every track whose step-th step is on.
+func mixStep(tracks []*Track, samples map[string][]float32, step int) []float32 {
+	var mix []float32
+
+	for _, t := range tracks {
+		if t.steps[step] != 1 {
+			continue
+		}
+
+		s := samples[t.name]
+		if len(s) > len(mix) {
+			longer, shorter := make([]float32, len(s)), mix
+			copy(longer, s)
+			mix, s = longer, shorter
+		}
+
+		for i, v := range s {
+			mix[i] += v
+		}
+	}
+
+	return mix
+}
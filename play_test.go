@@ -0,0 +1,98 @@
+package drum
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// memSampler is a Sampler backed by an in-memory instrument -> samples map.
+type memSampler map[string][]float32
+
+func (m memSampler) Sample(instrument string) ([]float32, error) {
+	return m[instrument], nil
+}
+
+// memOutput is an Output that records every step it is given.
+type memOutput struct {
+	steps [][]float32
+}
+
+func (o *memOutput) Write(samples []float32) error {
+	o.steps = append(o.steps, samples)
+	return nil
+}
+
+func TestPlayMixesActiveSteps(t *testing.T) {
+	p := NewPattern("0.808-alpha", 6000) // fast tempo so the test runs quickly
+
+	kick := NewTrack(0, "kick")
+	kick.SetStep(0, true)
+	p.AddTrack(kick)
+
+	snare := NewTrack(1, "snare")
+	snare.SetStep(0, true)
+	p.AddTrack(snare)
+
+	sampler := memSampler{
+		"kick":  {1, 1},
+		"snare": {2, 2, 2},
+	}
+	out := &memOutput{}
+
+	if err := p.Play(context.Background(), PlayOptions{Sampler: sampler, Output: out}); err != nil {
+		t.Fatalf("Play returned an error: %v", err)
+	}
+
+	if len(out.steps) != numSteps {
+		t.Fatalf("got %d steps, want %d", len(out.steps), numSteps)
+	}
+
+	want := []float32{3, 3, 2}
+	got := out.steps[0]
+	if len(got) != len(want) {
+		t.Fatalf("step 0 = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("step 0[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	for _, s := range out.steps[1:] {
+		if len(s) != 0 {
+			t.Errorf("expected inactive steps to mix to nothing, got %v", s)
+		}
+	}
+}
+
+func TestPlayHonorsContextCancellation(t *testing.T) {
+	p := NewPattern("0.808-alpha", 1) // slow tempo so cancellation wins the race
+	kick := NewTrack(0, "kick")
+	kick.SetStep(0, true)
+	p.AddTrack(kick)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := p.Play(ctx, PlayOptions{Sampler: memSampler{}, Output: &memOutput{}})
+	if err != context.Canceled {
+		t.Fatalf("got err %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Play took %v after cancellation, expected it to return promptly", elapsed)
+	}
+}
+
+func TestPlayRequiresSamplerAndOutput(t *testing.T) {
+	p := NewPattern("0.808-alpha", 120)
+
+	if err := p.Play(context.Background(), PlayOptions{Output: &memOutput{}}); err != ErrNoSampler {
+		t.Errorf("got err %v, want ErrNoSampler", err)
+	}
+
+	if err := p.Play(context.Background(), PlayOptions{Sampler: memSampler{}}); err != ErrNoOutput {
+		t.Errorf("got err %v, want ErrNoOutput", err)
+	}
+}
@@ -0,0 +1,163 @@
+package drum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+)
+
+// Decoder reads a .splice stream incrementally: the fixed-size header is
+// parsed by Header, then each track is read one at a time by NextTrack, so
+// a caller never needs to hold an entire pattern's bytes in memory at once.
+type Decoder struct {
+	r io.Reader
+
+	headerRead bool
+	hwver      string
+	tempo      float32
+
+	// remaining is the number of track-data bytes left to read, derived
+	// from the file's remaining-bytes field.
+	remaining uint64
+}
+
+// NewDecoder returns a Decoder that reads a .splice stream from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Header reads and validates the magic, remaining-bytes, hardware-version
+// and tempo fields, returning the hardware version and tempo. It is safe to
+// call more than once; subsequent calls return the values read the first
+// time. NextTrack calls Header itself if it hasn't been called yet.
+func (d *Decoder) Header() (hwver string, tempo float32, err error) {
+	if d.headerRead {
+		return d.hwver, d.tempo, nil
+	}
+
+	var magic [szFileIDFld]byte
+	if _, err := io.ReadFull(d.r, magic[:]); err != nil {
+		switch err {
+		case io.EOF:
+			return "", 0, ErrEmptySpliceFile
+		case io.ErrUnexpectedEOF:
+			return "", 0, ErrBadFileType
+		default:
+			return "", 0, err
+		}
+	}
+	if string(magic[:]) != "SPLICE" {
+		return "", 0, ErrBadFileType
+	}
+
+	var szBuf [szRemBytesFld]byte
+	if _, err := io.ReadFull(d.r, szBuf[:]); err != nil {
+		return "", 0, ErrNoRemBytesFld
+	}
+	bodyLen := binary.BigEndian.Uint64(szBuf[:])
+	if bodyLen < szHwVerFld+szTempoFld {
+		return "", 0, ErrInvalidNumBytes
+	}
+
+	hwverBuf := make([]byte, szHwVerFld)
+	if _, err := io.ReadFull(d.r, hwverBuf); err != nil {
+		return "", 0, ErrTruncatedHeader
+	}
+
+	var tempoBuf [szTempoFld]byte
+	if _, err := io.ReadFull(d.r, tempoBuf[:]); err != nil {
+		return "", 0, ErrTruncatedHeader
+	}
+	if err := binary.Read(bytes.NewReader(tempoBuf[:]), binary.LittleEndian, &d.tempo); err != nil {
+		return "", 0, err
+	}
+
+	// remaining[:szPlatFld] could end up with trailing zeros so we trim
+	// before converting to a string.
+	d.hwver = string(bytes.TrimRight(hwverBuf[:szPlatFld], string([]byte{0})))
+	d.remaining = bodyLen - szHwVerFld - szTempoFld
+	d.headerRead = true
+
+	return d.hwver, d.tempo, nil
+}
+
+// NextTrack reads and returns the next track in the stream. It returns
+// io.EOF once all of the pattern's tracks have been read.
+func (d *Decoder) NextTrack() (*Track, error) {
+	if !d.headerRead {
+		if _, _, err := d.Header(); err != nil {
+			return nil, err
+		}
+	}
+
+	if d.remaining == 0 {
+		return nil, io.EOF
+	}
+
+	var idBuf [4]byte
+	if _, err := io.ReadFull(d.r, idBuf[:]); err != nil {
+		return nil, ErrInvalidNumBytes
+	}
+
+	var lenBuf [1]byte
+	if _, err := io.ReadFull(d.r, lenBuf[:]); err != nil {
+		return nil, ErrInvalidNumBytes
+	}
+	namelen := int(lenBuf[0])
+
+	nameBuf := make([]byte, namelen)
+	if _, err := io.ReadFull(d.r, nameBuf); err != nil {
+		return nil, ErrInvalidNumBytes
+	}
+
+	var stepsBuf [numSteps]byte
+	if _, err := io.ReadFull(d.r, stepsBuf[:]); err != nil {
+		return nil, ErrInvalidNumBytes
+	}
+
+	d.remaining -= uint64(len(idBuf) + len(lenBuf) + namelen + len(stepsBuf))
+
+	t := &Track{id: uint(idBuf[0]), name: string(nameBuf)}
+	copy(t.steps[:], stepsBuf[:])
+
+	return t, nil
+}
+
+// Decode reads a complete pattern from r.
+func Decode(r io.Reader) (*Pattern, error) {
+	d := NewDecoder(r)
+
+	hwver, tempo, err := d.Header()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Pattern{hwver: hwver, tempo: tempo}
+
+	for {
+		t, err := d.NextTrack()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		p.tracks = append(p.tracks, t)
+	}
+
+	return p, nil
+}
+
+// DecodeFile decodes the drum machine file found at the provided path
+// and returns a pointer to a parsed pattern which is the entry point to the
+// rest of the data.
+func DecodeFile(path string) (*Pattern, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Decode(f)
+}
@@ -0,0 +1,100 @@
+package drum
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path"
+	"testing"
+)
+
+func TestDecodeMatchesDecodeFile(t *testing.T) {
+	fixture := path.Join("fixtures", "pattern_1.splice")
+
+	data, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("could not read %s: %v", fixture, err)
+	}
+
+	fromDecode, err := Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Decode returned an error: %v", err)
+	}
+
+	fromDecodeFile, err := DecodeFile(fixture)
+	if err != nil {
+		t.Fatalf("DecodeFile returned an error: %v", err)
+	}
+
+	if fromDecode.String() != fromDecodeFile.String() {
+		t.Fatalf("Decode and DecodeFile disagree\nDecode:\n%s\nDecodeFile:\n%s", fromDecode, fromDecodeFile)
+	}
+}
+
+func TestDecoderNextTrackIncrementally(t *testing.T) {
+	fixture := path.Join("fixtures", "pattern_1.splice")
+
+	data, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("could not read %s: %v", fixture, err)
+	}
+
+	d := NewDecoder(bytes.NewReader(data))
+
+	hwver, tempo, err := d.Header()
+	if err != nil {
+		t.Fatalf("Header returned an error: %v", err)
+	}
+	if hwver != "0.808-alpha" || tempo != 120 {
+		t.Fatalf("got hwver=%q tempo=%v, want hwver=%q tempo=120", hwver, tempo, "0.808-alpha")
+	}
+
+	var names []string
+	for {
+		track, err := d.NextTrack()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextTrack returned an error: %v", err)
+		}
+		names = append(names, track.name)
+	}
+
+	want := []string{"kick", "snare", "clap", "hh-open", "hh-close", "cowbell"}
+	if len(names) != len(want) {
+		t.Fatalf("got %d tracks, want %d", len(names), len(want))
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("track %d = %q, want %q", i, names[i], want[i])
+		}
+	}
+}
+
+func TestDecodeEmptyReader(t *testing.T) {
+	if _, err := Decode(bytes.NewReader(nil)); err != ErrEmptySpliceFile {
+		t.Fatalf("got %v, want ErrEmptySpliceFile", err)
+	}
+}
+
+func TestDecodeTruncatedHeader(t *testing.T) {
+	_, err := Decode(bytes.NewReader([]byte("SPLICE")))
+	if err != ErrNoRemBytesFld {
+		t.Fatalf("got %v, want ErrNoRemBytesFld", err)
+	}
+}
+
+func TestDecodeTruncatedBody(t *testing.T) {
+	fixture := path.Join("fixtures", "pattern_1.splice")
+
+	data, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("could not read %s: %v", fixture, err)
+	}
+
+	_, err = Decode(bytes.NewReader(data[:len(data)-5]))
+	if err != ErrInvalidNumBytes {
+		t.Fatalf("got %v, want ErrInvalidNumBytes", err)
+	}
+}